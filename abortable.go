@@ -0,0 +1,15 @@
+package amboy
+
+import "context"
+
+// AbortableRunner is implemented by Runners whose in-flight jobs can be
+// canceled individually by id, for callers that need to stop a single
+// misbehaving job without tearing down the whole pool.
+type AbortableRunner interface {
+	Runner
+
+	IsRunning(id string) bool
+	RunningJobs() []string
+	Abort(ctx context.Context, id string) error
+	AbortAll(ctx context.Context)
+}
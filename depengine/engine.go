@@ -0,0 +1,253 @@
+package depengine
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+)
+
+const (
+	minBackoff = 250 * time.Millisecond
+	maxBackoff = time.Minute
+
+	// watchPollInterval controls how often watch re-polls Queue.Results.
+	// Every queue implementation in this repo returns Results as a
+	// one-shot snapshot channel of currently-completed jobs rather than
+	// a live stream, so watch must re-invoke it on a timer instead of
+	// treating a single receive (or its close) as the whole event feed.
+	watchPollInterval = 100 * time.Millisecond
+)
+
+// Engine runs a graph of Manifolds on top of an amboy.Queue, re-enqueuing
+// a manifold whenever one of its declared inputs changes. It is safe for
+// concurrent use.
+type Engine struct {
+	queue amboy.Queue
+
+	mutex      sync.RWMutex
+	manifolds  map[string]Manifold
+	dependents map[string][]string
+	snapshot   map[string]interface{}
+	jobOwner   map[string]string
+	backoff    map[string]time.Duration
+
+	cancel context.CancelFunc
+}
+
+// NewEngine returns an Engine that dispatches manifold jobs through the
+// given queue. The queue must already have a runner attached and will be
+// started by Run.
+func NewEngine(q amboy.Queue) *Engine {
+	return &Engine{
+		queue:      q,
+		manifolds:  make(map[string]Manifold),
+		dependents: make(map[string][]string),
+		snapshot:   make(map[string]interface{}),
+		jobOwner:   make(map[string]string),
+		backoff:    make(map[string]time.Duration),
+	}
+}
+
+// Register adds a manifold to the engine. Register must be called before
+// Run; manifolds cannot be added once the engine is running.
+func (e *Engine) Register(m Manifold) error {
+	if err := m.validate(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.cancel != nil {
+		return errors.Errorf("cannot register manifold '%s' on a running engine", m.Name)
+	}
+	if _, ok := e.manifolds[m.Name]; ok {
+		return errors.Errorf("manifold '%s' is already registered", m.Name)
+	}
+	e.manifolds[m.Name] = m
+
+	for _, input := range m.Inputs {
+		e.dependents[input] = append(e.dependents[input], m.Name)
+	}
+
+	return nil
+}
+
+// Get implements Getter, and is passed to each manifold's Start function.
+func (e *Engine) Get(name string) (interface{}, bool) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	v, ok := e.snapshot[name]
+	return v, ok
+}
+
+// Run starts the queue, enqueues every registered manifold, and watches
+// Queue.Results to react to completions. Run blocks until ctx is
+// canceled or Close is called, at which point the shared context used
+// for all manifold jobs is canceled so that in-flight jobs stop.
+func (e *Engine) Run(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	e.mutex.Lock()
+	e.cancel = cancel
+	names := make([]string, 0, len(e.manifolds))
+	for name := range e.manifolds {
+		names = append(names, name)
+	}
+	e.mutex.Unlock()
+
+	if err := e.queue.Start(runCtx); err != nil {
+		cancel()
+		return errors.Wrap(err, "starting queue")
+	}
+
+	for _, name := range names {
+		if err := e.enqueue(runCtx, name); err != nil {
+			return errors.Wrapf(err, "enqueuing manifold '%s'", name)
+		}
+	}
+
+	e.watch(runCtx)
+
+	return runCtx.Err()
+}
+
+// Close cancels the engine's shared context, stopping all in-flight
+// manifold jobs.
+func (e *Engine) Close() {
+	e.mutex.RLock()
+	cancel := e.cancel
+	e.mutex.RUnlock()
+
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// watch polls Queue.Results on a timer, updates the snapshot via the
+// owning manifold's Output binder for each newly-completed job, and
+// re-enqueues every manifold that depends on it. Results is re-invoked
+// every tick rather than received from once, since it returns a fresh
+// snapshot of currently-completed jobs and closes rather than staying
+// open as a live stream. watch returns when ctx is canceled.
+func (e *Engine) watch(ctx context.Context) {
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for j := range e.queue.Results(ctx) {
+				e.handleCompletion(ctx, j)
+			}
+		}
+	}
+}
+
+func (e *Engine) handleCompletion(ctx context.Context, j amboy.Job) {
+	e.mutex.Lock()
+	name, ok := e.jobOwner[j.ID()]
+	if ok {
+		delete(e.jobOwner, j.ID())
+	}
+	m := e.manifolds[name]
+	e.mutex.Unlock()
+
+	if !ok {
+		return
+	}
+
+	if err := j.Error(); err != nil {
+		e.scheduleRetry(ctx, name, err)
+
+		// Dependents of an errored manifold are re-run against the
+		// snapshot as it stands.
+		e.mutex.RLock()
+		dependents := append([]string(nil), e.dependents[name]...)
+		e.mutex.RUnlock()
+		for _, dependent := range dependents {
+			_ = e.enqueue(ctx, dependent)
+		}
+		return
+	}
+
+	var output interface{}
+	if err := m.Output(j, &output); err != nil {
+		e.scheduleRetry(ctx, name, err)
+		return
+	}
+
+	e.mutex.Lock()
+	e.snapshot[name] = output
+	e.backoff[name] = 0
+	dependents := append([]string(nil), e.dependents[name]...)
+	e.mutex.Unlock()
+
+	// Only re-enqueue manifolds that depend on this output; re-enqueuing
+	// name itself here would re-run it forever with no triggering input
+	// change.
+	for _, dependent := range dependents {
+		_ = e.enqueue(ctx, dependent)
+	}
+}
+
+// scheduleRetry re-enqueues a manifold after an exponential backoff
+// delay, used when a manifold's job errors, or its Start function
+// reports ErrMissing or ErrBounce.
+func (e *Engine) scheduleRetry(ctx context.Context, name string, cause error) {
+	e.mutex.Lock()
+	wait := e.backoff[name]
+	if wait == 0 {
+		wait = minBackoff
+	} else {
+		wait *= 2
+		if wait > maxBackoff {
+			wait = maxBackoff
+		}
+	}
+	e.backoff[name] = wait
+	e.mutex.Unlock()
+
+	go func() {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+			_ = e.enqueue(ctx, name)
+		}
+	}()
+}
+
+// enqueue constructs a manifold's next job via its Start function and
+// puts it on the queue. A Start that returns ErrMissing or ErrBounce is
+// not treated as fatal; it simply schedules a backoff retry.
+func (e *Engine) enqueue(ctx context.Context, name string) error {
+	e.mutex.RLock()
+	m, ok := e.manifolds[name]
+	e.mutex.RUnlock()
+	if !ok {
+		return errors.Errorf("unknown manifold '%s'", name)
+	}
+
+	j, err := m.Start(ctx, e)
+	if errors.Is(err, ErrMissing) || errors.Is(err, ErrBounce) {
+		e.scheduleRetry(ctx, name, err)
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "starting manifold '%s'", name)
+	}
+
+	e.mutex.Lock()
+	e.jobOwner[j.ID()] = name
+	e.mutex.Unlock()
+
+	return errors.Wrap(e.queue.Put(ctx, j), "enqueuing job")
+}
@@ -0,0 +1,65 @@
+// Package depengine layers over an amboy.Queue to run a set of named,
+// long-lived jobs ("manifolds") whose declared inputs are the outputs of
+// other manifolds. When a dependency's output changes or errors, every
+// manifold that depends on it is automatically re-run with the fresh
+// snapshot. This gives amboy users a way to model long-running
+// interdependent workers (agents, pollers, connection managers) on top of
+// the existing queue/runner primitives, rather than only fire-and-forget
+// jobs.
+package depengine
+
+import (
+	"context"
+
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+)
+
+// ErrMissing is returned by a Manifold's Start function when one of its
+// declared Inputs is not yet available in the snapshot. The engine treats
+// this as transient and retries the manifold with exponential backoff.
+var ErrMissing = errors.New("depengine: required input is not yet available")
+
+// ErrBounce is returned by a Manifold's Start function (or surfaced via a
+// completed job's error) to request that the manifold be re-run without
+// treating the failure as fatal. Like ErrMissing, it triggers an
+// exponential backoff retry rather than abandoning the manifold.
+var ErrBounce = errors.New("depengine: manifold requested a bounce")
+
+// Getter exposes read access to the engine's current output snapshot so a
+// Manifold can resolve the values produced by the manifolds it depends
+// on.
+type Getter interface {
+	// Get returns the most recent output recorded for the named
+	// manifold and whether it has been produced at least once.
+	Get(name string) (interface{}, bool)
+}
+
+// Manifold describes one named, long-lived unit of work in the
+// dependency graph. Inputs lists the names of other manifolds whose
+// output this manifold reads via the Getter passed to Start. Start
+// constructs the amboy.Job to run next, typically reading its inputs via
+// the supplied Getter and returning ErrMissing if one isn't available
+// yet. Output binds a completed job's result onto the engine's snapshot
+// for this manifold's name.
+type Manifold struct {
+	Name   string
+	Inputs []string
+
+	Start  func(ctx context.Context, get Getter) (amboy.Job, error)
+	Output func(j amboy.Job, target *interface{}) error
+}
+
+func (m Manifold) validate() error {
+	if m.Name == "" {
+		return errors.New("manifold must have a name")
+	}
+	if m.Start == nil {
+		return errors.Errorf("manifold '%s' must define Start", m.Name)
+	}
+	if m.Output == nil {
+		return errors.Errorf("manifold '%s' must define Output", m.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package amboy
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Format identifies the wire encoding a queue uses to persist a job's
+// state, for the queue implementations that round-trip jobs through a
+// byte-oriented store (Pub/Sub, Redis) rather than a driver with its own
+// native document type.
+type Format int
+
+// JSON is the only Format amboy currently implements.
+const (
+	JSON Format = iota
+)
+
+// Marshal encodes v using the format.
+func (f Format) Marshal(v interface{}) ([]byte, error) {
+	switch f {
+	case JSON:
+		return json.Marshal(v)
+	default:
+		return nil, errors.Errorf("unsupported format '%d'", f)
+	}
+}
+
+// Unmarshal decodes data into v using the format.
+func (f Format) Unmarshal(data []byte, v interface{}) error {
+	switch f {
+	case JSON:
+		return json.Unmarshal(data, v)
+	default:
+		return errors.Errorf("unsupported format '%d'", f)
+	}
+}
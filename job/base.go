@@ -0,0 +1,201 @@
+// Package job provides several generically useful Job implementations,
+// including job.Base, the embeddable type most concrete jobs build on
+// top of to satisfy amboy.Job without re-implementing its bookkeeping.
+package job
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mongodb/amboy"
+)
+
+// Base is the embeddable core of most amboy.Job implementations. It
+// tracks the bookkeeping every job needs (id, type, timing, status,
+// scheduling) and implements amboy.ProgressReporter so that any job
+// built on top of it can stream state transitions and log lines to
+// Queue.SubscribeJob subscribers without additional work.
+type Base struct {
+	TaskID  string
+	JobType amboy.JobType
+
+	priority int
+	timeInfo amboy.JobTimeInfo
+	status   amboy.JobStatusInfo
+	runAt    time.Time
+
+	subscribers []chan amboy.JobEvent
+	done        bool
+
+	mutex sync.RWMutex
+}
+
+func (b *Base) ID() string { return b.TaskID }
+
+func (b *Base) SetID(id string) { b.TaskID = id }
+
+func (b *Base) Type() amboy.JobType { return b.JobType }
+
+func (b *Base) Priority() int { return b.priority }
+
+func (b *Base) SetPriority(p int) { b.priority = p }
+
+func (b *Base) TimeInfo() amboy.JobTimeInfo {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.timeInfo
+}
+
+func (b *Base) SetTimeInfo(info amboy.JobTimeInfo) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.timeInfo = info
+}
+
+// RunAt and SetRunAt implement amboy.Schedulable, so any job built on
+// Base can be deferred by a queue that supports it (see
+// queue.NewDelayedLocal) simply by calling SetRunAt.
+func (b *Base) RunAt() time.Time {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.runAt
+}
+
+func (b *Base) SetRunAt(t time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.runAt = t
+}
+
+func (b *Base) Status() amboy.JobStatusInfo {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.status
+}
+
+func (b *Base) SetStatus(status amboy.JobStatusInfo) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.status = status
+}
+
+// AddError records an error on the job's status and emits a
+// JobEventStateFailed event to any subscribers. It does not mark the job
+// complete; callers that want to stop processing should also call
+// MarkComplete.
+func (b *Base) AddError(err error) {
+	if err == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	b.status.ErrorCount++
+	b.status.Errors = append(b.status.Errors, err.Error())
+	b.mutex.Unlock()
+
+	b.publish(amboy.JobEvent{State: amboy.JobEventStateFailed, Message: err.Error()})
+}
+
+// Error returns a non-nil error if the job has recorded any errors.
+func (b *Base) Error() error {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if b.status.ErrorCount == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s", b.status.Errors[len(b.status.Errors)-1])
+}
+
+// MarkComplete flags the job as finished and emits a
+// JobEventStateComplete event, closing every subscriber channel since no
+// further events will be published for this job.
+func (b *Base) MarkComplete() {
+	b.mutex.Lock()
+	b.status.Completed = true
+	b.status.InProgress = false
+	b.mutex.Unlock()
+
+	b.publish(amboy.JobEvent{State: amboy.JobEventStateComplete})
+	b.closeSubscribers()
+}
+
+// Log formats a log line and streams it to any subscribers as a
+// JobEventStateLog event. The line is not stored on the job.
+func (b *Base) Log(format string, args ...interface{}) {
+	b.publish(amboy.JobEvent{
+		State:   amboy.JobEventStateLog,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// SetProgress streams a JobEventStateRunning event carrying the job's
+// progress toward total units of work, for rendering "n/total" style
+// status views.
+func (b *Base) SetProgress(done, total int) {
+	b.publish(amboy.JobEvent{
+		State: amboy.JobEventStateRunning,
+		Done:  done,
+		Total: total,
+	})
+}
+
+// Subscribe implements amboy.ProgressReporter. The returned channel
+// receives every subsequent event for this job and is closed once the
+// job calls MarkComplete. A job that has already completed by the time
+// Subscribe is called has no further events to send, so Subscribe
+// returns an already-closed channel rather than one that would block a
+// caller forever waiting on events that will never arrive.
+func (b *Base) Subscribe() <-chan amboy.JobEvent {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.done {
+		ch := make(chan amboy.JobEvent)
+		close(ch)
+		return ch
+	}
+
+	ch := make(chan amboy.JobEvent, 100)
+	b.subscribers = append(b.subscribers, ch)
+
+	return ch
+}
+
+// publish sends event to every subscriber, dropping it for a subscriber
+// whose buffer is full rather than blocking the job on a slow consumer.
+// Progress events are a best-effort status feed, not a delivery
+// guarantee: Queue.Get/Job.Status remain the source of truth.
+func (b *Base) publish(event amboy.JobEvent) {
+	event.JobID = b.ID()
+	event.Timestamp = time.Now()
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (b *Base) closeSubscribers() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.done = true
+	for _, ch := range b.subscribers {
+		close(ch)
+	}
+	b.subscribers = nil
+}
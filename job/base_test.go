@@ -0,0 +1,60 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseProgressStreaming(t *testing.T) {
+	b := &Base{}
+	b.SetID("job-1")
+
+	events := b.Subscribe()
+
+	b.Log("starting up")
+	b.SetProgress(1, 2)
+	b.MarkComplete()
+
+	var seen []amboy.JobEvent
+	for event := range events {
+		seen = append(seen, event)
+	}
+
+	assert.Len(t, seen, 3)
+	assert.Equal(t, amboy.JobEventStateLog, seen[0].State)
+	assert.Equal(t, "starting up", seen[0].Message)
+	assert.Equal(t, amboy.JobEventStateRunning, seen[1].State)
+	assert.Equal(t, 1, seen[1].Done)
+	assert.Equal(t, 2, seen[1].Total)
+	assert.Equal(t, amboy.JobEventStateComplete, seen[2].State)
+
+	assert.True(t, b.Status().Completed)
+}
+
+func TestBaseAddErrorRecordsStatus(t *testing.T) {
+	b := &Base{}
+	b.SetID("job-2")
+
+	b.AddError(assert.AnError)
+
+	assert.Equal(t, 1, b.Status().ErrorCount)
+	assert.Error(t, b.Error())
+}
+
+func TestSubscribeAfterCompleteReturnsClosedChannel(t *testing.T) {
+	b := &Base{}
+	b.SetID("job-3")
+	b.MarkComplete()
+
+	events := b.Subscribe()
+
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok, "channel for an already-completed job should be closed, not block")
+	case <-time.After(time.Second):
+		t.Fatal("Subscribe on a completed job blocked instead of returning a closed channel")
+	}
+}
@@ -0,0 +1,57 @@
+package job
+
+import (
+	"context"
+
+	"github.com/mongodb/amboy"
+)
+
+// Stage is one named step of a multiStageJob. Jobs built with
+// NewMultiStageJob run their stages in order, emitting a
+// JobEventStateStage event before each one starts.
+type Stage struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+type multiStageJob struct {
+	Base
+
+	stages []Stage
+}
+
+// NewMultiStageJob returns a job that runs the given stages in order,
+// emitting a stage-boundary event (via Base.Log and a
+// JobEventStateStage event) before each stage starts. It stops at the
+// first stage that returns an error, or when ctx is canceled, and records
+// that error on the job. In every case it marks itself complete when Run
+// returns.
+func NewMultiStageJob(id string, stages []Stage) amboy.Job {
+	j := &multiStageJob{stages: stages}
+	j.SetID(id)
+	j.JobType = amboy.JobType{Name: "multi-stage", Version: 0}
+
+	return j
+}
+
+func (j *multiStageJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	total := len(j.stages)
+	for i, stage := range j.stages {
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			return
+		}
+
+		j.publish(amboy.JobEvent{State: amboy.JobEventStateStage, Stage: stage.Name})
+		j.Log("starting stage %s", stage.Name)
+
+		if err := stage.Run(ctx); err != nil {
+			j.AddError(err)
+			return
+		}
+
+		j.SetProgress(i+1, total)
+	}
+}
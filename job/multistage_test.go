@@ -0,0 +1,52 @@
+package job
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mongodb/amboy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiStageJobRunsStagesInOrder(t *testing.T) {
+	var order []string
+
+	j := NewMultiStageJob("multi-1", []Stage{
+		{Name: "first", Run: func(ctx context.Context) error {
+			order = append(order, "first")
+			return nil
+		}},
+		{Name: "second", Run: func(ctx context.Context) error {
+			order = append(order, "second")
+			return nil
+		}},
+	})
+
+	j.Run(context.Background())
+
+	assert.Equal(t, []string{"first", "second"}, order)
+	assert.True(t, j.Status().Completed)
+}
+
+func TestMultiStageJobStopsOnError(t *testing.T) {
+	var ran []string
+
+	j := NewMultiStageJob("multi-2", []Stage{
+		{Name: "first", Run: func(ctx context.Context) error {
+			ran = append(ran, "first")
+			return assert.AnError
+		}},
+		{Name: "second", Run: func(ctx context.Context) error {
+			ran = append(ran, "second")
+			return nil
+		}},
+	})
+
+	j.Run(context.Background())
+
+	assert.Equal(t, []string{"first"}, ran)
+	assert.Equal(t, 1, j.Status().ErrorCount)
+
+	_, ok := j.(amboy.ProgressReporter)
+	assert.True(t, ok)
+}
@@ -0,0 +1,29 @@
+package amboy
+
+import "time"
+
+// JobType provides a way to identify the type and version of a job, for
+// the registry to use when de/serializing jobs generically.
+type JobType struct {
+	Name    string
+	Version int
+}
+
+// JobTimeInfo records the timestamps relevant to a job's lifecycle.
+type JobTimeInfo struct {
+	Created time.Time
+	Start   time.Time
+	End     time.Time
+}
+
+// JobStatusInfo describes a job's current execution state, as reported
+// by Job.Status and recorded by Job.SetStatus.
+type JobStatusInfo struct {
+	ID         string
+	Owner      string
+	Completed  bool
+	InProgress bool
+	ModTime    time.Time
+	ErrorCount int
+	Errors     []string
+}
@@ -0,0 +1,8 @@
+package amboy
+
+// PanicHandler is invoked by a worker pool whenever it recovers from a
+// job's Run method panicking. Implementations can use this hook to report
+// the failure to external logging or metrics systems; a nil PanicHandler
+// disables the callback, but the panic is still recovered and converted
+// into a job error regardless.
+type PanicHandler func(job Job, recovered interface{}, stack []byte)
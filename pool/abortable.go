@@ -0,0 +1,174 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+)
+
+// abortableWorkers is an amboy.Runner that, like localWorkers, runs size
+// worker goroutines pulling from a queue, but additionally tracks each
+// in-flight job's cancel function so that a caller can abort a specific
+// job by id via Abort.
+type abortableWorkers struct {
+	size  int
+	queue amboy.Queue
+	opts  Options
+
+	started  bool
+	canceler context.CancelFunc
+	wg       sync.WaitGroup
+
+	running map[string]context.CancelFunc
+	mutex   sync.Mutex
+}
+
+// NewAbortableWorkers returns a Runner backed by size worker goroutines
+// whose in-flight jobs can be canceled individually via Abort.
+func NewAbortableWorkers(size int, opts Options) amboy.AbortableRunner {
+	if size <= 0 {
+		size = 1
+	}
+
+	return &abortableWorkers{
+		size:    size,
+		opts:    opts,
+		running: make(map[string]context.CancelFunc),
+	}
+}
+
+func (r *abortableWorkers) Started() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.started
+}
+
+func (r *abortableWorkers) SetQueue(q amboy.Queue) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.started {
+		return errors.New("cannot set queue on a started runner")
+	}
+	r.queue = q
+
+	return nil
+}
+
+func (r *abortableWorkers) Start(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.started {
+		return nil
+	}
+	if r.queue == nil {
+		return errors.New("cannot start runner without a queue")
+	}
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	r.canceler = cancel
+
+	for i := 0; i < r.size; i++ {
+		r.wg.Add(1)
+		go r.worker(workerCtx)
+	}
+
+	r.started = true
+
+	return nil
+}
+
+func (r *abortableWorkers) worker(ctx context.Context) {
+	defer r.wg.Done()
+
+	for ctx.Err() == nil {
+		j := r.queue.Next(ctx)
+		if j == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(idleBackoff):
+			}
+			continue
+		}
+
+		jobCtx, cancel := context.WithCancel(ctx)
+		r.mutex.Lock()
+		r.running[j.ID()] = cancel
+		r.mutex.Unlock()
+
+		runJob(jobCtx, j, r.opts.PanicHandler)
+
+		r.mutex.Lock()
+		delete(r.running, j.ID())
+		r.mutex.Unlock()
+		cancel()
+
+		_ = r.queue.Complete(ctx, j)
+	}
+}
+
+// Abort cancels the context of the job running with the given id, if
+// any, implementing amboy.AbortableRunner. It returns an error if no job
+// with that id is currently running.
+func (r *abortableWorkers) Abort(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	cancel, ok := r.running[id]
+	r.mutex.Unlock()
+
+	if !ok {
+		return errors.Errorf("job '%s' is not running", id)
+	}
+	cancel()
+
+	return nil
+}
+
+// AbortAll cancels every currently-running job's context.
+func (r *abortableWorkers) AbortAll(ctx context.Context) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, cancel := range r.running {
+		cancel()
+	}
+}
+
+// IsRunning reports whether a job with the given id is currently running.
+func (r *abortableWorkers) IsRunning(id string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	_, ok := r.running[id]
+	return ok
+}
+
+// RunningJobs returns the ids of every currently-running job.
+func (r *abortableWorkers) RunningJobs() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	ids := make([]string, 0, len(r.running))
+	for id := range r.running {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func (r *abortableWorkers) Close(ctx context.Context) {
+	r.mutex.Lock()
+	cancel := r.canceler
+	r.mutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	r.wg.Wait()
+}
@@ -0,0 +1,108 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+)
+
+// localWorkers is the basic amboy.Runner implementation: size worker
+// goroutines that pull jobs from a queue's Next method, run them through
+// processNext (which recovers panics via runJob), and loop until their
+// context is canceled.
+type localWorkers struct {
+	size  int
+	queue amboy.Queue
+	opts  Options
+
+	started  bool
+	canceler context.CancelFunc
+	wg       sync.WaitGroup
+
+	mutex sync.Mutex
+}
+
+// NewLocalWorkers returns a Runner backed by size worker goroutines. opts
+// configures cross-cutting behavior shared by all of amboy's local
+// runners, notably PanicHandler.
+func NewLocalWorkers(size int, opts Options) amboy.Runner {
+	if size <= 0 {
+		size = 1
+	}
+
+	return &localWorkers{size: size, opts: opts}
+}
+
+func (r *localWorkers) Started() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.started
+}
+
+func (r *localWorkers) SetQueue(q amboy.Queue) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.started {
+		return errors.New("cannot set queue on a started runner")
+	}
+	r.queue = q
+
+	return nil
+}
+
+func (r *localWorkers) Start(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.started {
+		return nil
+	}
+	if r.queue == nil {
+		return errors.New("cannot start runner without a queue")
+	}
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	r.canceler = cancel
+
+	for i := 0; i < r.size; i++ {
+		r.wg.Add(1)
+		go r.worker(workerCtx)
+	}
+
+	r.started = true
+
+	return nil
+}
+
+func (r *localWorkers) worker(ctx context.Context) {
+	defer r.wg.Done()
+
+	for ctx.Err() == nil {
+		if processNext(ctx, r.queue, r.opts) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(idleBackoff):
+		}
+	}
+}
+
+func (r *localWorkers) Close(ctx context.Context) {
+	r.mutex.Lock()
+	cancel := r.canceler
+	r.mutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	r.wg.Wait()
+}
@@ -0,0 +1,54 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalWorkersSurviveManyPanickingJobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const numJobs = 25
+
+	var mutex sync.Mutex
+	var numHandled int
+
+	runner := NewLocalWorkers(4, Options{
+		PanicHandler: func(job amboy.Job, recovered interface{}, stack []byte) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			numHandled++
+		},
+	})
+
+	q := NewQueueTester(runner)
+	require := assert.New(t)
+	require.NoError(q.Start(ctx))
+
+	for j := range jobsChanWithPanicingJobs(ctx, numJobs) {
+		require.NoError(q.Put(ctx, j))
+	}
+
+	deadline := time.After(5 * time.Second)
+	for {
+		mutex.Lock()
+		handled := numHandled
+		mutex.Unlock()
+
+		if handled == numJobs {
+			break
+		}
+
+		select {
+		case <-deadline:
+			t.Fatalf("only %d/%d panicking jobs were handled in time", handled, numJobs)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mongodb/amboy"
@@ -77,6 +78,24 @@ func (q *QueueTester) Get(ctx context.Context, name string) (amboy.Job, bool) {
 	return job, ok
 }
 
+// SubscribeJob returns a channel of amboy.JobEvents for the named job,
+// for callers that want to render a live status view instead of polling
+// Get/Status. The job must implement amboy.ProgressReporter, which every
+// job built on job.Base does.
+func (q *QueueTester) SubscribeJob(ctx context.Context, id string) (<-chan amboy.JobEvent, error) {
+	j, ok := q.Get(ctx, id)
+	if !ok {
+		return nil, errors.Errorf("no job named '%s'", id)
+	}
+
+	reporter, ok := j.(amboy.ProgressReporter)
+	if !ok {
+		return nil, errors.Errorf("job '%s' does not support progress subscriptions", id)
+	}
+
+	return reporter.Subscribe(), nil
+}
+
 func (q *QueueTester) Info() amboy.QueueInfo {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -124,12 +143,38 @@ func (q *QueueTester) SetRunner(r amboy.Runner) error {
 	return nil
 }
 
+// notYetDueBackoff bounds how long Next waits before re-checking a job
+// it put back because it wasn't due yet, so a single far-future
+// scheduled job doesn't starve the worker into a tight re-queue loop.
+const notYetDueBackoff = 10 * time.Millisecond
+
 func (q *QueueTester) Next(ctx context.Context) amboy.Job {
-	select {
-	case <-ctx.Done():
-		return nil
-	case job := <-q.toProcess:
-		return job
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case job := <-q.toProcess:
+			if sched, ok := job.(amboy.Schedulable); ok {
+				if wait := time.Until(sched.RunAt()); wait > 0 {
+					// Put the job back rather than sleeping here with
+					// it dequeued: sleeping on a held job blocks this
+					// worker from ever dispatching any other job that's
+					// already ready, including ones queued behind it.
+					select {
+					case q.toProcess <- job:
+					default:
+					}
+
+					select {
+					case <-ctx.Done():
+						return nil
+					case <-time.After(notYetDueBackoff):
+					}
+					continue
+				}
+			}
+			return job
+		}
 	}
 }
 
@@ -0,0 +1,72 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/mongodb/amboy"
+)
+
+// Options holds configuration shared by amboy's local worker pool
+// implementations (local, abortable, single, and rate-limited).
+// PanicHandler, when set, is called any time a worker recovers from a
+// panicking job, in addition to the panic always being converted into a
+// job error so the queue can still call Complete on the job.
+type Options struct {
+	PanicHandler amboy.PanicHandler
+}
+
+// idleBackoff bounds how long a worker sleeps after Next reports no job
+// is available, so a queue whose Next doesn't block (e.g. returns nil
+// immediately instead of waiting for work) can't spin a worker goroutine
+// at 100% CPU.
+const idleBackoff = 10 * time.Millisecond
+
+// runJob executes a job's Run method, recovering from any panic so that a
+// single malfunctioning job cannot take down a runner's worker goroutine.
+// On panic, it records the stack trace and a job error on the job's
+// status and marks the job complete (with an error), so that the calling
+// runner can proceed to call queue.Complete and continue processing the
+// next job exactly as it would for a job that failed normally. Every
+// local runner (local, abortable, single, and rate-limited) should run
+// jobs through this helper rather than calling j.Run directly.
+func runJob(ctx context.Context, j amboy.Job, onPanic amboy.PanicHandler) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := debug.Stack()
+
+			j.AddError(fmt.Errorf("job panicked: %v\n%s", r, stack))
+			j.MarkComplete()
+
+			if onPanic != nil {
+				onPanic(j, r, stack)
+			}
+		}
+	}()
+
+	j.Run(ctx)
+}
+
+// processNext pulls the next job off of queue and, if one is available,
+// runs it through runJob and reports the result via queue.Complete. The
+// local, single, and rate-limited runners drive their worker loops through
+// this helper so that a panicking job is recovered and still completed
+// exactly like any other job, rather than escaping the worker goroutine;
+// the abortable runner calls runJob and queue.Complete itself so that it
+// can give each job its own cancelable context. It reports whether a job was actually dispatched,
+// so that a caller whose queue returns nil without blocking can back off
+// instead of busy-spinning.
+func processNext(ctx context.Context, queue amboy.Queue, opts Options) bool {
+	j := queue.Next(ctx)
+	if j == nil {
+		return false
+	}
+
+	runJob(ctx, j, opts.PanicHandler)
+
+	_ = queue.Complete(ctx, j)
+
+	return true
+}
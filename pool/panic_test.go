@@ -0,0 +1,75 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunJobRecoversFromPanic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	j := &jobThatPanics{}
+
+	var handled bool
+	var recoveredVal interface{}
+	runJob(ctx, j, func(job amboy.Job, recovered interface{}, stack []byte) {
+		handled = true
+		recoveredVal = recovered
+		require.NotEmpty(t, stack)
+	})
+
+	assert.True(t, handled)
+	assert.Equal(t, "panic err", recoveredVal)
+	assert.True(t, j.Status().Completed)
+	assert.True(t, len(j.Status().Errors) > 0)
+}
+
+func TestPoolSurvivesManyPanickingJobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const numJobs = 25
+	jobs := jobsChanWithPanicingJobs(ctx, numJobs)
+
+	var mutex sync.Mutex
+	var numHandled int
+
+	const numWorkers = 4
+	wg := &sync.WaitGroup{}
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				runJob(ctx, j, func(job amboy.Job, recovered interface{}, stack []byte) {
+					mutex.Lock()
+					defer mutex.Unlock()
+					numHandled++
+				})
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pool did not process all panicking jobs in time")
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, numJobs, numHandled)
+}
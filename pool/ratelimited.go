@@ -0,0 +1,111 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+)
+
+// rateLimitedWorkers is an amboy.Runner that, like localWorkers, runs
+// size worker goroutines, but throttles how often each worker may pull a
+// new job from the queue to at most once per interval.
+type rateLimitedWorkers struct {
+	size     int
+	interval time.Duration
+	queue    amboy.Queue
+	opts     Options
+
+	started  bool
+	canceler context.CancelFunc
+	wg       sync.WaitGroup
+
+	mutex sync.Mutex
+}
+
+// NewRateLimitedWorkers returns a Runner backed by size worker goroutines,
+// each of which waits at least interval between dispatching successive
+// jobs.
+func NewRateLimitedWorkers(size int, interval time.Duration, opts Options) amboy.Runner {
+	if size <= 0 {
+		size = 1
+	}
+	if interval <= 0 {
+		interval = time.Millisecond
+	}
+
+	return &rateLimitedWorkers{size: size, interval: interval, opts: opts}
+}
+
+func (r *rateLimitedWorkers) Started() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.started
+}
+
+func (r *rateLimitedWorkers) SetQueue(q amboy.Queue) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.started {
+		return errors.New("cannot set queue on a started runner")
+	}
+	r.queue = q
+
+	return nil
+}
+
+func (r *rateLimitedWorkers) Start(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.started {
+		return nil
+	}
+	if r.queue == nil {
+		return errors.New("cannot start runner without a queue")
+	}
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	r.canceler = cancel
+
+	for i := 0; i < r.size; i++ {
+		r.wg.Add(1)
+		go r.worker(workerCtx)
+	}
+
+	r.started = true
+
+	return nil
+}
+
+func (r *rateLimitedWorkers) worker(ctx context.Context) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			processNext(ctx, r.queue, r.opts)
+		}
+	}
+}
+
+func (r *rateLimitedWorkers) Close(ctx context.Context) {
+	r.mutex.Lock()
+	cancel := r.canceler
+	r.mutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	r.wg.Wait()
+}
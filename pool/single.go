@@ -0,0 +1,97 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+)
+
+// singleWorker is an amboy.Runner that processes jobs one at a time on a
+// single goroutine, for callers that want deterministic, serial
+// execution (e.g. tests) rather than a concurrent pool.
+type singleWorker struct {
+	queue amboy.Queue
+	opts  Options
+
+	started  bool
+	canceler context.CancelFunc
+	wg       sync.WaitGroup
+
+	mutex sync.Mutex
+}
+
+// NewSingleRunner returns a Runner that processes jobs serially on a
+// single worker goroutine.
+func NewSingleRunner(opts Options) amboy.Runner {
+	return &singleWorker{opts: opts}
+}
+
+func (r *singleWorker) Started() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.started
+}
+
+func (r *singleWorker) SetQueue(q amboy.Queue) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.started {
+		return errors.New("cannot set queue on a started runner")
+	}
+	r.queue = q
+
+	return nil
+}
+
+func (r *singleWorker) Start(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.started {
+		return nil
+	}
+	if r.queue == nil {
+		return errors.New("cannot start runner without a queue")
+	}
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	r.canceler = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		for workerCtx.Err() == nil {
+			if processNext(workerCtx, r.queue, r.opts) {
+				continue
+			}
+
+			select {
+			case <-workerCtx.Done():
+				return
+			case <-time.After(idleBackoff):
+			}
+		}
+	}()
+
+	r.started = true
+
+	return nil
+}
+
+func (r *singleWorker) Close(ctx context.Context) {
+	r.mutex.Lock()
+	cancel := r.canceler
+	r.mutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	r.wg.Wait()
+}
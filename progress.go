@@ -0,0 +1,43 @@
+package amboy
+
+import "time"
+
+// JobEventState describes the kind of transition or update a JobEvent
+// represents.
+type JobEventState string
+
+// The recognized JobEventState values. Jobs that implement multiple
+// stages (see job.NewMultiStageJob) use JobEventStateStage for each
+// stage boundary, with the stage name recorded on the event.
+const (
+	JobEventStateQueued   JobEventState = "queued"
+	JobEventStateRunning  JobEventState = "running"
+	JobEventStateStage    JobEventState = "stage"
+	JobEventStateLog      JobEventState = "log"
+	JobEventStateComplete JobEventState = "complete"
+	JobEventStateFailed   JobEventState = "failed"
+)
+
+// JobEvent describes a single state transition, progress update, or
+// streamed log line emitted by a running job. Consumers read these off
+// of the channel returned by Queue.SubscribeJob to render a live status
+// view of a job, rather than polling Queue.Get/Job.Status.
+type JobEvent struct {
+	JobID     string
+	State     JobEventState
+	Stage     string
+	Message   string
+	Done      int
+	Total     int
+	Timestamp time.Time
+}
+
+// ProgressReporter is implemented by jobs that support streaming state
+// transitions, stage boundaries, and log lines to interested
+// subscribers. job.Base implements this interface, so any job built on
+// top of it gets streaming support for free.
+type ProgressReporter interface {
+	// Subscribe returns a channel of JobEvents for this job. The
+	// channel is closed when the job completes.
+	Subscribe() <-chan JobEvent
+}
@@ -0,0 +1,311 @@
+package queue
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/pool"
+	"github.com/pkg/errors"
+)
+
+// delayedJobHeap is a container/heap.Interface over jobs ordered by their
+// scheduled run-at time, earliest first, so the queue can always find the
+// soonest-eligible job without scanning every pending job.
+type delayedJobHeap []amboy.Job
+
+func (h delayedJobHeap) Len() int { return len(h) }
+
+func (h delayedJobHeap) Less(i, j int) bool {
+	return runAt(h[i]).Before(runAt(h[j]))
+}
+
+func (h delayedJobHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *delayedJobHeap) Push(x interface{}) {
+	*h = append(*h, x.(amboy.Job))
+}
+
+func (h *delayedJobHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// runAt returns a job's scheduled dispatch time, or the zero time for
+// jobs that don't opt into deferred dispatch by implementing
+// amboy.Schedulable.
+func runAt(j amboy.Job) time.Time {
+	if sched, ok := j.(amboy.Schedulable); ok {
+		return sched.RunAt()
+	}
+	return time.Time{}
+}
+
+// delayedLocalQueue is an in-memory queue that dispatches jobs in run-at
+// order and will not return a job from Next until its scheduled time has
+// arrived, on top of the same naive worker-pool model as the other local
+// queue implementations.
+type delayedLocalQueue struct {
+	id      string
+	pending delayedJobHeap
+	storage map[string]amboy.Job
+	runner  amboy.Runner
+	started bool
+	wake    chan struct{}
+
+	mutex sync.Mutex
+}
+
+// NewDelayedLocal returns an in-memory, single-process queue that
+// dispatches jobs in run-at order. Jobs that implement amboy.Schedulable
+// are withheld from Next until their RunAt has arrived; all other jobs
+// are eligible immediately, as if RunAt were the zero time. size
+// configures the number of workers in the queue's default pool.
+func NewDelayedLocal(size int) (amboy.Queue, error) {
+	if size <= 0 {
+		return nil, errors.New("must specify a positive pool size")
+	}
+
+	q := &delayedLocalQueue{
+		id:      uuid.New().String(),
+		storage: make(map[string]amboy.Job),
+		wake:    make(chan struct{}, 1),
+	}
+	heap.Init(&q.pending)
+
+	runner := pool.NewLocalWorkers(size, pool.Options{})
+	if err := runner.SetQueue(q); err != nil {
+		return nil, errors.Wrap(err, "attaching default worker pool")
+	}
+	q.runner = runner
+
+	return q, nil
+}
+
+func (q *delayedLocalQueue) ID() string { return "queue.local.delayed." + q.id }
+
+func (q *delayedLocalQueue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *delayedLocalQueue) Put(ctx context.Context, j amboy.Job) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+
+	q.mutex.Lock()
+	heap.Push(&q.pending, j)
+	q.storage[j.ID()] = j
+	q.mutex.Unlock()
+
+	q.notify()
+	return nil
+}
+
+func (q *delayedLocalQueue) Get(ctx context.Context, name string) (amboy.Job, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	j, ok := q.storage[name]
+	return j, ok
+}
+
+func (q *delayedLocalQueue) Save(ctx context.Context, j amboy.Job) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if _, ok := q.storage[j.ID()]; !ok {
+		return nil
+	}
+	q.storage[j.ID()] = j
+	return nil
+}
+
+// Next blocks until either a pending job's scheduled run-at time has
+// arrived or the context is canceled. Whenever Put adds a job, Next
+// wakes up to re-evaluate whether that job is now the soonest-eligible
+// one, so a newly scheduled job never has to wait behind an older job
+// with a later run-at.
+func (q *delayedLocalQueue) Next(ctx context.Context) amboy.Job {
+	for {
+		q.mutex.Lock()
+		if len(q.pending) == 0 {
+			q.mutex.Unlock()
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-q.wake:
+				continue
+			}
+		}
+
+		next := q.pending[0]
+		wait := time.Until(runAt(next))
+		if wait <= 0 {
+			heap.Pop(&q.pending)
+			q.mutex.Unlock()
+			return next
+		}
+		q.mutex.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		case <-q.wake:
+			timer.Stop()
+		}
+	}
+}
+
+func (q *delayedLocalQueue) Complete(ctx context.Context, j amboy.Job) error {
+	return nil
+}
+
+func (q *delayedLocalQueue) Stats(ctx context.Context) amboy.QueueStats {
+	q.mutex.Lock()
+	jobs := make([]amboy.Job, 0, len(q.storage))
+	for _, j := range q.storage {
+		jobs = append(jobs, j)
+	}
+	q.mutex.Unlock()
+
+	stats := amboy.QueueStats{Total: len(jobs)}
+	for _, j := range jobs {
+		switch {
+		case j.Status().Completed:
+			stats.Completed++
+		case j.Status().InProgress:
+			stats.Running++
+		default:
+			stats.Pending++
+		}
+	}
+
+	return stats
+}
+
+func (q *delayedLocalQueue) Results(ctx context.Context) <-chan amboy.Job {
+	out := make(chan amboy.Job)
+
+	go func() {
+		defer close(out)
+
+		q.mutex.Lock()
+		jobs := make([]amboy.Job, 0, len(q.storage))
+		for _, j := range q.storage {
+			jobs = append(jobs, j)
+		}
+		q.mutex.Unlock()
+
+		for _, j := range jobs {
+			if !j.Status().Completed {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- j:
+			}
+		}
+	}()
+
+	return out
+}
+
+func (q *delayedLocalQueue) JobInfo(ctx context.Context) <-chan amboy.JobInfo {
+	out := make(chan amboy.JobInfo)
+
+	go func() {
+		defer close(out)
+
+		q.mutex.Lock()
+		jobs := make([]amboy.Job, 0, len(q.storage))
+		for _, j := range q.storage {
+			jobs = append(jobs, j)
+		}
+		q.mutex.Unlock()
+
+		for _, j := range jobs {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- amboy.NewJobInfo(j):
+			}
+		}
+	}()
+
+	return out
+}
+
+// SubscribeJob streams state transitions and log lines for the named
+// job; see pool.QueueTester.SubscribeJob for the semantics.
+func (q *delayedLocalQueue) SubscribeJob(ctx context.Context, id string) (<-chan amboy.JobEvent, error) {
+	j, ok := q.Get(ctx, id)
+	if !ok {
+		return nil, errors.Errorf("no job named '%s'", id)
+	}
+
+	reporter, ok := j.(amboy.ProgressReporter)
+	if !ok {
+		return nil, errors.Errorf("job '%s' does not support progress subscriptions", id)
+	}
+
+	return reporter.Subscribe(), nil
+}
+
+func (q *delayedLocalQueue) Info() amboy.QueueInfo {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return amboy.QueueInfo{
+		Started:     q.started,
+		LockTimeout: amboy.LockTimeout,
+	}
+}
+
+func (q *delayedLocalQueue) Runner() amboy.Runner { return q.runner }
+
+func (q *delayedLocalQueue) SetRunner(r amboy.Runner) error {
+	if q.Info().Started {
+		return errors.New("cannot set runner on active queue")
+	}
+	q.runner = r
+	return nil
+}
+
+func (q *delayedLocalQueue) Start(ctx context.Context) error {
+	if q.Info().Started {
+		return nil
+	}
+
+	if q.runner == nil {
+		return errors.New("cannot start queue without a runner")
+	}
+
+	if err := q.runner.Start(ctx); err != nil {
+		return errors.Wrap(err, "starting worker pool")
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.started = true
+
+	return nil
+}
+
+func (q *delayedLocalQueue) Close(ctx context.Context) {}
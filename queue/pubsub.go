@@ -0,0 +1,451 @@
+package queue
+
+import (
+	"context"
+	"sync"
+
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/pubsub"
+	"github.com/google/uuid"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+	"google.golang.org/api/iterator"
+)
+
+// CloudPubSubQueueCreationOptions describes the configuration required to
+// construct a queue backed by Google Cloud Pub/Sub. Job state and results
+// are persisted to a companion Firestore collection so that Get, Save, and
+// Results behave correctly across a fleet of independent workers, mirroring
+// the split between dispatch and storage used by the Mongo-backed queues.
+type CloudPubSubQueueCreationOptions struct {
+	ProjectID           string
+	Topic               string
+	Subscription        string
+	FirestoreCollection string
+	WorkerPoolSize      int
+
+	client          *pubsub.Client
+	firestoreClient *firestore.Client
+}
+
+func (opts *CloudPubSubQueueCreationOptions) validate() error {
+	if opts.ProjectID == "" {
+		return errors.New("must specify a GCP project id")
+	}
+	if opts.Topic == "" {
+		return errors.New("must specify a pub/sub topic")
+	}
+	if opts.Subscription == "" {
+		return errors.New("must specify a pub/sub subscription")
+	}
+	if opts.FirestoreCollection == "" {
+		opts.FirestoreCollection = "amboy.jobs"
+	}
+	if opts.WorkerPoolSize <= 0 {
+		opts.WorkerPoolSize = 2
+	}
+
+	return nil
+}
+
+// cloudPubSubQueue implements amboy.Queue using a Pub/Sub topic for job
+// dispatch and a Firestore collection for durable job state, so that
+// results and status survive across independent worker processes without
+// requiring a MongoDB deployment.
+type cloudPubSubQueue struct {
+	id      string
+	opts    CloudPubSubQueueCreationOptions
+	topic   *pubsub.Topic
+	sub     *pubsub.Subscription
+	fs      *firestore.CollectionRef
+	runner  amboy.Runner
+	started bool
+
+	retryHandler amboy.RetryHandler
+
+	// inFlight tracks messages that have been dispatched by Next but not
+	// yet acknowledged. The message is only acked once Complete is
+	// called for the job.
+	inFlight map[string]*pubsub.Message
+
+	// jobs receives decoded jobs from the long-lived receive loop started
+	// in Start. receiveCancel and receiveDone stop that loop and let
+	// Close wait for it to exit.
+	jobs          chan amboy.Job
+	receiveCancel context.CancelFunc
+	receiveDone   chan struct{}
+
+	mu sync.RWMutex
+}
+
+// NewCloudPubSubQueue constructs a remote queue that dispatches jobs over a
+// Google Cloud Pub/Sub topic/subscription pair and stores job state and
+// results in Firestore. It implements the full amboy.Queue interface so it
+// can be used as a drop-in alternative to the Mongo-backed remote queues
+// for users who are already on GCP and would rather not run Mongo.
+func NewCloudPubSubQueue(ctx context.Context, opts CloudPubSubQueueCreationOptions) (amboy.Queue, error) {
+	if err := opts.validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid queue options")
+	}
+
+	if opts.client == nil {
+		client, err := pubsub.NewClient(ctx, opts.ProjectID)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating pub/sub client")
+		}
+		opts.client = client
+	}
+
+	if opts.firestoreClient == nil {
+		fsClient, err := firestore.NewClient(ctx, opts.ProjectID)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating firestore client")
+		}
+		opts.firestoreClient = fsClient
+	}
+
+	sub := opts.client.Subscription(opts.Subscription)
+	// Let the Pub/Sub client library itself keep extending the ack
+	// deadline for as long as a message is outstanding, up to
+	// amboy.LockTimeout, instead of amboy doing it by hand: Message has
+	// no exported Extend/Modify method to do that with.
+	sub.ReceiveSettings.MaxExtension = amboy.LockTimeout
+
+	q := &cloudPubSubQueue{
+		id:       uuid.New().String(),
+		opts:     opts,
+		topic:    opts.client.Topic(opts.Topic),
+		sub:      sub,
+		fs:       opts.firestoreClient.Collection(opts.FirestoreCollection),
+		inFlight: make(map[string]*pubsub.Message),
+		jobs:     make(chan amboy.Job, opts.WorkerPoolSize),
+	}
+
+	return q, nil
+}
+
+func (q *cloudPubSubQueue) ID() string { return "pubsub.queue." + q.id }
+
+func (q *cloudPubSubQueue) Put(ctx context.Context, j amboy.Job) error {
+	payload, err := registry.Marshal(j, amboy.JSON)
+	if err != nil {
+		return errors.Wrap(err, "encoding job")
+	}
+
+	if _, err := q.fs.Doc(j.ID()).Set(ctx, map[string]interface{}{
+		"job":    string(payload),
+		"status": j.Status(),
+	}); err != nil {
+		return errors.Wrap(err, "persisting job state to firestore")
+	}
+
+	result := q.topic.Publish(ctx, &pubsub.Message{
+		Data:       payload,
+		Attributes: map[string]string{"job_id": j.ID()},
+	})
+	if _, err := result.Get(ctx); err != nil {
+		return errors.Wrap(err, "publishing job")
+	}
+
+	return nil
+}
+
+func (q *cloudPubSubQueue) Get(ctx context.Context, name string) (amboy.Job, bool) {
+	doc, err := q.fs.Doc(name).Get(ctx)
+	if err != nil {
+		return nil, false
+	}
+
+	j, err := q.jobFromDoc(doc)
+	if err != nil {
+		return nil, false
+	}
+
+	return j, true
+}
+
+func (q *cloudPubSubQueue) jobFromDoc(doc *firestore.DocumentSnapshot) (amboy.Job, error) {
+	data := doc.Data()
+	raw, ok := data["job"].(string)
+	if !ok {
+		return nil, errors.New("document did not contain a job payload")
+	}
+
+	j, err := registry.Unmarshal([]byte(raw), amboy.JSON)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding job")
+	}
+
+	return j, nil
+}
+
+// startReceiving launches the subscription's long-lived Receive loop,
+// which manages its own concurrency and redelivery internally and is
+// meant to be called once per subscription lifetime. The loop decodes
+// each message into a job and hands it to Next over q.jobs, leaving the
+// message outstanding (neither acked nor nacked) until Complete is called
+// for the job once it finishes running. This means a worker that crashes
+// mid-run lets the message redeliver rather than losing the job.
+func (q *cloudPubSubQueue) startReceiving(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	q.receiveCancel = cancel
+	q.receiveDone = make(chan struct{})
+
+	go func() {
+		defer close(q.receiveDone)
+
+		_ = q.sub.Receive(ctx, func(_ context.Context, msg *pubsub.Message) {
+			j, err := registry.Unmarshal(msg.Data, amboy.JSON)
+			if err != nil {
+				msg.Nack()
+				return
+			}
+
+			q.mu.Lock()
+			q.inFlight[j.ID()] = msg
+			q.mu.Unlock()
+
+			select {
+			case q.jobs <- j:
+			case <-ctx.Done():
+				q.mu.Lock()
+				delete(q.inFlight, j.ID())
+				q.mu.Unlock()
+				msg.Nack()
+			}
+		})
+	}()
+}
+
+// Next returns the next decoded job produced by the long-lived receive
+// loop started in Start, blocking until one is available or the context
+// is canceled.
+func (q *cloudPubSubQueue) Next(ctx context.Context) amboy.Job {
+	select {
+	case <-ctx.Done():
+		return nil
+	case j := <-q.jobs:
+		return j
+	}
+}
+
+// Complete acks the job's underlying Pub/Sub message now that processing
+// has actually finished, then persists the job's final state. Acking
+// here, rather than at dispatch in Next, means a worker that crashes
+// mid-run leaves the message outstanding so it redelivers instead of
+// being silently dropped.
+func (q *cloudPubSubQueue) Complete(ctx context.Context, j amboy.Job) error {
+	q.mu.Lock()
+	msg, ok := q.inFlight[j.ID()]
+	if ok {
+		delete(q.inFlight, j.ID())
+	}
+	q.mu.Unlock()
+
+	if ok {
+		msg.Ack()
+	}
+
+	if rj, ok := j.(amboy.RetryableJob); ok && q.retryHandler != nil && rj.RetryInfo().ShouldRetry() {
+		if err := q.retryHandler.Put(ctx, rj); err != nil {
+			return errors.Wrap(err, "queueing retry")
+		}
+	}
+
+	return q.Save(ctx, j)
+}
+
+func (q *cloudPubSubQueue) Save(ctx context.Context, j amboy.Job) error {
+	payload, err := registry.Marshal(j, amboy.JSON)
+	if err != nil {
+		return errors.Wrap(err, "encoding job")
+	}
+
+	_, err = q.fs.Doc(j.ID()).Set(ctx, map[string]interface{}{
+		"job":    string(payload),
+		"status": j.Status(),
+	})
+
+	return errors.Wrap(err, "saving job state to firestore")
+}
+
+func (q *cloudPubSubQueue) Results(ctx context.Context) <-chan amboy.Job {
+	out := make(chan amboy.Job)
+
+	go func() {
+		defer close(out)
+
+		iter := q.fs.Documents(ctx)
+		defer iter.Stop()
+
+		for {
+			doc, err := iter.Next()
+			if err == iterator.Done {
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			j, err := q.jobFromDoc(doc)
+			if err != nil || !j.Status().Completed {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- j:
+			}
+		}
+	}()
+
+	return out
+}
+
+func (q *cloudPubSubQueue) JobInfo(ctx context.Context) <-chan amboy.JobInfo {
+	out := make(chan amboy.JobInfo)
+
+	go func() {
+		defer close(out)
+
+		iter := q.fs.Documents(ctx)
+		defer iter.Stop()
+
+		for {
+			doc, err := iter.Next()
+			if err == iterator.Done {
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			j, err := q.jobFromDoc(doc)
+			if err != nil {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- amboy.NewJobInfo(j):
+			}
+		}
+	}()
+
+	return out
+}
+
+func (q *cloudPubSubQueue) Stats(ctx context.Context) amboy.QueueStats {
+	stats := amboy.QueueStats{}
+
+	iter := q.fs.Documents(ctx)
+	defer iter.Stop()
+
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			break
+		}
+
+		j, err := q.jobFromDoc(doc)
+		if err != nil {
+			continue
+		}
+
+		stats.Total++
+		if j.Status().Completed {
+			stats.Completed++
+		} else if j.Status().InProgress {
+			stats.Running++
+		} else {
+			stats.Pending++
+		}
+	}
+
+	return stats
+}
+
+func (q *cloudPubSubQueue) Info() amboy.QueueInfo {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return amboy.QueueInfo{
+		Started:     q.started,
+		LockTimeout: amboy.LockTimeout,
+	}
+}
+
+func (q *cloudPubSubQueue) Runner() amboy.Runner { return q.runner }
+
+func (q *cloudPubSubQueue) SetRunner(r amboy.Runner) error {
+	if q.Info().Started {
+		return errors.New("cannot set runner on active queue")
+	}
+	q.runner = r
+	return nil
+}
+
+// SetRetryHandler configures the RetryHandler that Complete hands
+// retryable jobs off to, implementing amboy.RetryableQueue.
+func (q *cloudPubSubQueue) SetRetryHandler(rh amboy.RetryHandler) error {
+	if q.Info().Started {
+		return errors.New("cannot set retry handler on active queue")
+	}
+
+	if err := rh.SetQueue(q); err != nil {
+		return errors.Wrap(err, "setting queue on retry handler")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.retryHandler = rh
+
+	return nil
+}
+
+func (q *cloudPubSubQueue) Start(ctx context.Context) error {
+	if q.Info().Started {
+		return nil
+	}
+
+	if q.runner == nil {
+		return errors.New("cannot start queue without a runner")
+	}
+
+	if err := q.runner.Start(ctx); err != nil {
+		return errors.Wrap(err, "starting worker pool")
+	}
+
+	if q.retryHandler != nil {
+		if err := q.retryHandler.Start(ctx); err != nil {
+			return errors.Wrap(err, "starting retry handler")
+		}
+	}
+
+	q.startReceiving(ctx)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.started = true
+
+	return nil
+}
+
+func (q *cloudPubSubQueue) Close(ctx context.Context) {
+	if q.receiveCancel != nil {
+		q.receiveCancel()
+		<-q.receiveDone
+	}
+
+	q.topic.Stop()
+
+	if q.retryHandler != nil {
+		q.retryHandler.Close(ctx)
+	}
+}
@@ -0,0 +1,324 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+const redisPollInterval = 250 * time.Millisecond
+
+// redisQueue implements amboy.Queue against a Redis-compatible backend,
+// using a list for pending dispatch, a sorted set (scored by run-at) for
+// scheduled and retrying jobs, and a hash for durable job state. It is a
+// lighter-weight distributed alternative to the Mongo-backed queues for
+// users who already run Redis.
+type redisQueue struct {
+	id      string
+	client  RedisClient
+	runner  amboy.Runner
+	started bool
+
+	retryHandler amboy.RetryHandler
+}
+
+// NewRedisQueue returns a queue backed by the given RedisClient, which
+// may be a real connection or, in tests, the in-repo emulator in
+// queue/redis/mock.
+func NewRedisQueue(client RedisClient) (amboy.Queue, error) {
+	if client == nil {
+		return nil, errors.New("must specify a redis client")
+	}
+
+	return &redisQueue{
+		id:     uuid.New().String(),
+		client: client,
+	}, nil
+}
+
+func (q *redisQueue) ID() string { return "queue.redis." + q.id }
+
+func (q *redisQueue) pendingKey() string   { return fmt.Sprintf("amboy.%s.pending", q.id) }
+func (q *redisQueue) scheduledKey() string { return fmt.Sprintf("amboy.%s.scheduled", q.id) }
+func (q *redisQueue) stateKey() string     { return fmt.Sprintf("amboy.%s.state", q.id) }
+
+func (q *redisQueue) encode(j amboy.Job) (string, error) {
+	payload, err := registry.Marshal(j, amboy.JSON)
+	if err != nil {
+		return "", errors.Wrap(err, "encoding job")
+	}
+	return string(payload), nil
+}
+
+func (q *redisQueue) decode(payload string) (amboy.Job, error) {
+	j, err := registry.Unmarshal([]byte(payload), amboy.JSON)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding job")
+	}
+	return j, nil
+}
+
+func (q *redisQueue) Put(ctx context.Context, j amboy.Job) error {
+	payload, err := q.encode(j)
+	if err != nil {
+		return err
+	}
+
+	if err := q.client.HSet(ctx, q.stateKey(), j.ID(), payload); err != nil {
+		return errors.Wrap(err, "persisting job state")
+	}
+
+	if sched, ok := j.(amboy.Schedulable); ok && sched.RunAt().After(time.Now()) {
+		return errors.Wrap(
+			q.client.ZAdd(ctx, q.scheduledKey(), float64(sched.RunAt().UnixNano()), j.ID()),
+			"scheduling job",
+		)
+	}
+
+	return errors.Wrap(q.client.RPush(ctx, q.pendingKey(), j.ID()), "enqueuing job")
+}
+
+func (q *redisQueue) Get(ctx context.Context, name string) (amboy.Job, bool) {
+	payload, ok, err := q.client.HGet(ctx, q.stateKey(), name)
+	if err != nil || !ok {
+		return nil, false
+	}
+
+	j, err := q.decode(payload)
+	if err != nil {
+		return nil, false
+	}
+
+	return j, true
+}
+
+// promoteScheduled moves every scheduled/retry job whose run-at has
+// arrived from the sorted set onto the pending list. ZRem's removed
+// return value is the claim: when two workers race to promote the same
+// due id, only the one that actually removes it from the sorted set
+// pushes it onto the pending list, so the job is never double-dispatched.
+func (q *redisQueue) promoteScheduled(ctx context.Context) error {
+	due, err := q.client.ZRangeByScore(ctx, q.scheduledKey(), float64(time.Now().UnixNano()))
+	if err != nil {
+		return errors.Wrap(err, "checking scheduled jobs")
+	}
+
+	for _, id := range due {
+		removed, err := q.client.ZRem(ctx, q.scheduledKey(), id)
+		if err != nil || !removed {
+			continue
+		}
+		if err := q.client.RPush(ctx, q.pendingKey(), id); err != nil {
+			return errors.Wrap(err, "promoting scheduled job")
+		}
+	}
+
+	return nil
+}
+
+// Next promotes any due scheduled jobs and blocks for the next pending
+// job id, periodically re-checking the scheduled set so a job that
+// becomes due while Next is blocked is not left waiting behind it.
+func (q *redisQueue) Next(ctx context.Context) amboy.Job {
+	for {
+		if err := q.promoteScheduled(ctx); err != nil {
+			return nil
+		}
+
+		popCtx, cancel := context.WithTimeout(ctx, redisPollInterval)
+		id, err := q.client.BLPop(popCtx, q.pendingKey())
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			continue
+		}
+
+		payload, ok, err := q.client.HGet(ctx, q.stateKey(), id)
+		if err != nil || !ok {
+			continue
+		}
+
+		j, err := q.decode(payload)
+		if err != nil {
+			continue
+		}
+
+		return j
+	}
+}
+
+func (q *redisQueue) Complete(ctx context.Context, j amboy.Job) error {
+	if rj, ok := j.(amboy.RetryableJob); ok && q.retryHandler != nil && rj.RetryInfo().ShouldRetry() {
+		if err := q.retryHandler.Put(ctx, rj); err != nil {
+			return errors.Wrap(err, "queueing retry")
+		}
+	}
+
+	return q.Save(ctx, j)
+}
+
+func (q *redisQueue) Save(ctx context.Context, j amboy.Job) error {
+	payload, err := q.encode(j)
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrap(q.client.HSet(ctx, q.stateKey(), j.ID(), payload), "saving job state")
+}
+
+func (q *redisQueue) all(ctx context.Context) ([]amboy.Job, error) {
+	raw, err := q.client.HGetAll(ctx, q.stateKey())
+	if err != nil {
+		return nil, errors.Wrap(err, "listing job state")
+	}
+
+	jobs := make([]amboy.Job, 0, len(raw))
+	for _, payload := range raw {
+		j, err := q.decode(payload)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+
+	return jobs, nil
+}
+
+func (q *redisQueue) Stats(ctx context.Context) amboy.QueueStats {
+	jobs, err := q.all(ctx)
+	if err != nil {
+		return amboy.QueueStats{}
+	}
+
+	stats := amboy.QueueStats{Total: len(jobs)}
+	for _, j := range jobs {
+		switch {
+		case j.Status().Completed:
+			stats.Completed++
+		case j.Status().InProgress:
+			stats.Running++
+		default:
+			stats.Pending++
+		}
+	}
+
+	return stats
+}
+
+func (q *redisQueue) Results(ctx context.Context) <-chan amboy.Job {
+	out := make(chan amboy.Job)
+
+	go func() {
+		defer close(out)
+
+		jobs, err := q.all(ctx)
+		if err != nil {
+			return
+		}
+
+		for _, j := range jobs {
+			if !j.Status().Completed {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case out <- j:
+			}
+		}
+	}()
+
+	return out
+}
+
+func (q *redisQueue) JobInfo(ctx context.Context) <-chan amboy.JobInfo {
+	out := make(chan amboy.JobInfo)
+
+	go func() {
+		defer close(out)
+
+		jobs, err := q.all(ctx)
+		if err != nil {
+			return
+		}
+
+		for _, j := range jobs {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- amboy.NewJobInfo(j):
+			}
+		}
+	}()
+
+	return out
+}
+
+func (q *redisQueue) Info() amboy.QueueInfo {
+	return amboy.QueueInfo{
+		Started:     q.started,
+		LockTimeout: amboy.LockTimeout,
+	}
+}
+
+func (q *redisQueue) Runner() amboy.Runner { return q.runner }
+
+func (q *redisQueue) SetRunner(r amboy.Runner) error {
+	if q.started {
+		return errors.New("cannot set runner on active queue")
+	}
+	q.runner = r
+	return nil
+}
+
+// SetRetryHandler configures the RetryHandler that Complete hands
+// retryable jobs off to, implementing amboy.RetryableQueue.
+func (q *redisQueue) SetRetryHandler(rh amboy.RetryHandler) error {
+	if q.started {
+		return errors.New("cannot set retry handler on active queue")
+	}
+
+	if err := rh.SetQueue(q); err != nil {
+		return errors.Wrap(err, "setting queue on retry handler")
+	}
+
+	q.retryHandler = rh
+	return nil
+}
+
+func (q *redisQueue) Start(ctx context.Context) error {
+	if q.started {
+		return nil
+	}
+
+	if q.runner == nil {
+		return errors.New("cannot start queue without a runner")
+	}
+
+	if err := q.runner.Start(ctx); err != nil {
+		return errors.Wrap(err, "starting worker pool")
+	}
+
+	if q.retryHandler != nil {
+		if err := q.retryHandler.Start(ctx); err != nil {
+			return errors.Wrap(err, "starting retry handler")
+		}
+	}
+
+	q.started = true
+	return nil
+}
+
+func (q *redisQueue) Close(ctx context.Context) {
+	if q.retryHandler != nil {
+		q.retryHandler.Close(ctx)
+	}
+}
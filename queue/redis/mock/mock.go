@@ -0,0 +1,165 @@
+// Package mock provides a small, hand-written in-memory emulator of the
+// subset of Redis that queue.NewRedisQueue depends on (lists, sorted
+// sets, and hashes), so that contributors can exercise the Redis queue's
+// code paths in tests without a running Redis server.
+package mock
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+type scoredMember struct {
+	member string
+	score  float64
+}
+
+// Client is an in-memory stand-in for a Redis connection. It implements
+// the same method set as queue.RedisClient so it can be passed directly
+// to queue.NewRedisQueue in tests. Create one with NewClient; the zero
+// value has no maps allocated and panics on its first write.
+type Client struct {
+	mutex sync.Mutex
+
+	lists  map[string][]string
+	sets   map[string][]scoredMember
+	hashes map[string]map[string]string
+
+	pushed chan struct{}
+}
+
+// NewClient returns an empty mock Redis client.
+func NewClient() *Client {
+	return &Client{
+		lists:  make(map[string][]string),
+		sets:   make(map[string][]scoredMember),
+		hashes: make(map[string]map[string]string),
+		pushed: make(chan struct{}, 1),
+	}
+}
+
+func (c *Client) notify() {
+	select {
+	case c.pushed <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Client) RPush(ctx context.Context, key string, value string) error {
+	c.mutex.Lock()
+	c.lists[key] = append(c.lists[key], value)
+	c.mutex.Unlock()
+
+	c.notify()
+	return nil
+}
+
+// BLPop blocks until a value is available on the named list or the
+// context is canceled, mirroring Redis's blocking left-pop semantics
+// closely enough for conformance tests.
+func (c *Client) BLPop(ctx context.Context, key string) (string, error) {
+	for {
+		c.mutex.Lock()
+		list := c.lists[key]
+		if len(list) > 0 {
+			value := list[0]
+			c.lists[key] = list[1:]
+			c.mutex.Unlock()
+			return value, nil
+		}
+		c.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-c.pushed:
+		}
+	}
+}
+
+func (c *Client) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	members := c.sets[key]
+	for i, m := range members {
+		if m.member == member {
+			members[i].score = score
+			return nil
+		}
+	}
+	c.sets[key] = append(members, scoredMember{member: member, score: score})
+	return nil
+}
+
+func (c *Client) ZRangeByScore(ctx context.Context, key string, max float64) ([]string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	members := append([]scoredMember(nil), c.sets[key]...)
+	sort.Slice(members, func(i, j int) bool { return members[i].score < members[j].score })
+
+	out := make([]string, 0, len(members))
+	for _, m := range members {
+		if m.score <= max {
+			out = append(out, m.member)
+		}
+	}
+	return out, nil
+}
+
+// ZRem removes member from the set at key, under the client's single
+// mutex, so it can be used as an atomic claim: exactly one caller racing
+// to remove the same member gets removed == true.
+func (c *Client) ZRem(ctx context.Context, key string, member string) (bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	members := c.sets[key]
+	for i, m := range members {
+		if m.member == member {
+			c.sets[key] = append(members[:i], members[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *Client) HSet(ctx context.Context, key, field, value string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.hashes[key] == nil {
+		c.hashes[key] = make(map[string]string)
+	}
+	c.hashes[key][field] = value
+	return nil
+}
+
+func (c *Client) HGet(ctx context.Context, key, field string) (string, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	value, ok := c.hashes[key][field]
+	return value, ok, nil
+}
+
+func (c *Client) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	out := make(map[string]string, len(c.hashes[key]))
+	for k, v := range c.hashes[key] {
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (c *Client) HDel(ctx context.Context, key, field string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.hashes[key], field)
+	return nil
+}
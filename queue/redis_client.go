@@ -0,0 +1,27 @@
+package queue
+
+import "context"
+
+// RedisClient is the minimal surface queue.NewRedisQueue needs from a
+// Redis connection: lists for pending dispatch, a sorted set for
+// scheduled/retry jobs, and a hash for durable job state. Factoring it
+// out as an interface lets tests inject either a real client (e.g. a
+// thin wrapper around go-redis) or queue/redis/mock's in-repo emulator.
+type RedisClient interface {
+	RPush(ctx context.Context, key string, value string) error
+	BLPop(ctx context.Context, key string) (string, error)
+
+	ZAdd(ctx context.Context, key string, score float64, member string) error
+	ZRangeByScore(ctx context.Context, key string, max float64) ([]string, error)
+	// ZRem removes member from the sorted set at key and reports whether
+	// it was actually present to remove. Callers that need to claim a
+	// due scheduled/retry job exclusively (so two concurrent workers
+	// can't both dispatch it) must only act on a removal when removed
+	// is true.
+	ZRem(ctx context.Context, key string, member string) (removed bool, err error)
+
+	HSet(ctx context.Context, key, field, value string) error
+	HGet(ctx context.Context, key, field string) (string, bool, error)
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	HDel(ctx context.Context, key, field string) error
+}
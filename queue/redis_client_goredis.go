@@ -0,0 +1,81 @@
+package queue
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+)
+
+// goRedisClient adapts a go-redis client to the RedisClient interface
+// that queue.NewRedisQueue depends on.
+type goRedisClient struct {
+	client *redis.Client
+}
+
+// newRealRedisClient connects to the Redis server at url and returns a
+// RedisClient backed by it, for use outside of tests or against a real
+// server set via AMBOY_TEST_REDIS_URL.
+func newRealRedisClient(url string) (RedisClient, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing redis url")
+	}
+
+	return &goRedisClient{client: redis.NewClient(opts)}, nil
+}
+
+func (c *goRedisClient) RPush(ctx context.Context, key string, value string) error {
+	return c.client.RPush(ctx, key, value).Err()
+}
+
+func (c *goRedisClient) BLPop(ctx context.Context, key string) (string, error) {
+	result, err := c.client.BLPop(ctx, 0, key).Result()
+	if err != nil {
+		return "", err
+	}
+	if len(result) < 2 {
+		return "", errors.New("unexpected BLPOP response")
+	}
+	return result[1], nil
+}
+
+func (c *goRedisClient) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	return c.client.ZAdd(ctx, key, &redis.Z{Score: score, Member: member}).Err()
+}
+
+func (c *goRedisClient) ZRangeByScore(ctx context.Context, key string, max float64) ([]string, error) {
+	return c.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatFloat(max, 'f', -1, 64),
+	}).Result()
+}
+
+func (c *goRedisClient) ZRem(ctx context.Context, key string, member string) (bool, error) {
+	removed, err := c.client.ZRem(ctx, key, member).Result()
+	return removed > 0, err
+}
+
+func (c *goRedisClient) HSet(ctx context.Context, key, field, value string) error {
+	return c.client.HSet(ctx, key, field, value).Err()
+}
+
+func (c *goRedisClient) HGet(ctx context.Context, key, field string) (string, bool, error) {
+	value, err := c.client.HGet(ctx, key, field).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+func (c *goRedisClient) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return c.client.HGetAll(ctx, key).Result()
+}
+
+func (c *goRedisClient) HDel(ctx context.Context, key, field string) error {
+	return c.client.HDel(ctx, key, field).Err()
+}
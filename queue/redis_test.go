@@ -0,0 +1,252 @@
+package queue
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/queue/redis/mock"
+	"github.com/mongodb/amboy/registry"
+	"github.com/stretchr/testify/suite"
+)
+
+type redisTestJob struct {
+	job.Base
+}
+
+func newRedisTestJob(id string) *redisTestJob {
+	j := &redisTestJob{}
+	j.SetID(id)
+	j.JobType = amboy.JobType{Name: "redis-test-job", Version: 0}
+	return j
+}
+
+func (j *redisTestJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+}
+
+func init() {
+	registry.AddJobType("redis-test-job", func() amboy.Job { return newRedisTestJob("") })
+	registry.AddJobType("redis-retryable-test-job", func() amboy.Job { return newRetryableTestJob("", false) })
+}
+
+// retryableTestJob exercises the amboy.RetryableJob path through
+// redisQueue.Complete.
+type retryableTestJob struct {
+	job.Base
+
+	retryInfo amboy.JobRetryInfo
+}
+
+func newRetryableTestJob(id string, needsRetry bool) *retryableTestJob {
+	j := &retryableTestJob{}
+	j.SetID(id)
+	j.JobType = amboy.JobType{Name: "redis-retryable-test-job", Version: 0}
+	j.retryInfo = amboy.JobRetryInfo{Retryable: true, NeedsRetry: needsRetry, MaxAttempts: 3}
+	return j
+}
+
+func (j *retryableTestJob) Run(ctx context.Context) { defer j.MarkComplete() }
+
+func (j *retryableTestJob) RetryInfo() amboy.JobRetryInfo { return j.retryInfo }
+
+func (j *retryableTestJob) UpdateRetryInfo(info amboy.JobRetryInfo) { j.retryInfo = info }
+
+// fakeRetryHandler records every job handed to it by Complete, so tests
+// can assert that a retryable job is queued for retry instead of being
+// treated as finished.
+type fakeRetryHandler struct {
+	mutex   sync.Mutex
+	retried []string
+}
+
+func (h *fakeRetryHandler) SetQueue(amboy.Queue) error { return nil }
+
+func (h *fakeRetryHandler) Start(context.Context) error { return nil }
+
+func (h *fakeRetryHandler) Close(context.Context) {}
+
+func (h *fakeRetryHandler) Put(ctx context.Context, j amboy.RetryableJob) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.retried = append(h.retried, j.ID())
+	return nil
+}
+
+func (h *fakeRetryHandler) retriedIDs() []string {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return append([]string(nil), h.retried...)
+}
+
+// RedisSuite is a conformance suite that runs the same checks against
+// both queue/redis/mock's in-repo emulator and, when AMBOY_TEST_REDIS_URL
+// is set, a real Redis server. This lets contributors exercise the Redis
+// queue's code paths without a running server, while CI can still verify
+// against the genuine article.
+type RedisSuite struct {
+	suite.Suite
+
+	client RedisClient
+	queue  *redisQueue
+}
+
+func TestRedisSuiteMock(t *testing.T) {
+	suite.Run(t, &RedisSuite{client: mock.NewClient()})
+}
+
+func TestRedisSuiteReal(t *testing.T) {
+	url := os.Getenv("AMBOY_TEST_REDIS_URL")
+	if url == "" {
+		t.Skip("set AMBOY_TEST_REDIS_URL to run conformance tests against a real redis server")
+	}
+
+	client, err := newRealRedisClient(url)
+	if err != nil {
+		t.Fatalf("connecting to redis at %s: %v", url, err)
+	}
+
+	suite.Run(t, &RedisSuite{client: client})
+}
+
+func (s *RedisSuite) SetupTest() {
+	q, err := NewRedisQueue(s.client)
+	s.Require().NoError(err)
+	s.queue = q.(*redisQueue)
+}
+
+func (s *RedisSuite) TestPutAndGet() {
+	ctx := context.Background()
+	j := newRedisTestJob("put-get")
+
+	s.Require().NoError(s.queue.Put(ctx, j))
+
+	fetched, ok := s.queue.Get(ctx, j.ID())
+	s.Require().True(ok)
+	s.Equal(j.ID(), fetched.ID())
+}
+
+func (s *RedisSuite) TestNextDispatchesPendingJob() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	j := newRedisTestJob("next")
+	s.Require().NoError(s.queue.Put(ctx, j))
+
+	next := s.queue.Next(ctx)
+	s.Require().NotNil(next)
+	s.Equal(j.ID(), next.ID())
+}
+
+func (s *RedisSuite) TestCompleteAndSavePersistStatus() {
+	ctx := context.Background()
+	j := newRedisTestJob("complete")
+	s.Require().NoError(s.queue.Put(ctx, j))
+
+	j.Run(ctx)
+	s.Require().NoError(s.queue.Complete(ctx, j))
+
+	fetched, ok := s.queue.Get(ctx, j.ID())
+	s.Require().True(ok)
+	s.True(fetched.Status().Completed)
+}
+
+func (s *RedisSuite) TestStatsCountsJobs() {
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		s.Require().NoError(s.queue.Put(ctx, newRedisTestJob(s.uniqueID())))
+	}
+
+	stats := s.queue.Stats(ctx)
+	s.Equal(3, stats.Total)
+	s.Equal(3, stats.Pending)
+}
+
+func (s *RedisSuite) TestScheduledJobNotDispatchedEarly() {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	j := newRedisTestJob("scheduled")
+	j.SetRunAt(time.Now().Add(time.Hour))
+	s.Require().NoError(s.queue.Put(ctx, j))
+
+	s.Nil(s.queue.Next(ctx))
+}
+
+func (s *RedisSuite) TestCompleteQueuesRetryForRetryableJob() {
+	ctx := context.Background()
+
+	handler := &fakeRetryHandler{}
+	s.Require().NoError(s.queue.SetRetryHandler(handler))
+
+	j := newRetryableTestJob("retry-me", true)
+	s.Require().NoError(s.queue.Put(ctx, j))
+
+	j.Run(ctx)
+	s.Require().NoError(s.queue.Complete(ctx, j))
+
+	s.Equal([]string{"retry-me"}, handler.retriedIDs())
+}
+
+func (s *RedisSuite) TestCompleteDoesNotRetryWhenNotNeeded() {
+	ctx := context.Background()
+
+	handler := &fakeRetryHandler{}
+	s.Require().NoError(s.queue.SetRetryHandler(handler))
+
+	j := newRetryableTestJob("no-retry", false)
+	s.Require().NoError(s.queue.Put(ctx, j))
+
+	j.Run(ctx)
+	s.Require().NoError(s.queue.Complete(ctx, j))
+
+	s.Empty(handler.retriedIDs())
+}
+
+// TestPromoteScheduledIsClaimedAtMostOnce guards against regressing to a
+// ZRem-then-RPush sequence that isn't atomic: with N workers racing to
+// promote the same due job, exactly one RPush should happen.
+func (s *RedisSuite) TestPromoteScheduledIsClaimedAtMostOnce() {
+	ctx := context.Background()
+
+	j := newRedisTestJob("race")
+	j.SetRunAt(time.Now().Add(time.Hour))
+	s.Require().NoError(s.queue.Put(ctx, j))
+	// Re-score the scheduled job into the past so it is due for promotion.
+	s.Require().NoError(s.queue.client.ZAdd(ctx, s.queue.scheduledKey(), float64(time.Now().Add(-time.Second).UnixNano()), j.ID()))
+
+	const numWorkers = 8
+	wg := &sync.WaitGroup{}
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = s.queue.promoteScheduled(ctx)
+		}()
+	}
+	wg.Wait()
+
+	var dispatched int
+	for {
+		popCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		_, err := s.queue.client.BLPop(popCtx, s.queue.pendingKey())
+		cancel()
+		if err != nil {
+			break
+		}
+		dispatched++
+	}
+
+	s.Equal(1, dispatched)
+}
+
+func (s *RedisSuite) uniqueID() string {
+	return uuid.New().String()
+}
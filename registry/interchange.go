@@ -0,0 +1,80 @@
+package registry
+
+import (
+	"encoding/json"
+
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+)
+
+// JobInterchange is the wire representation of a job: the registered type
+// name used to look up a factory for its concrete type, the job's status,
+// and a Format-encoded snapshot of the job's own exported state.
+type JobInterchange struct {
+	Name   string
+	Type   amboy.JobType
+	Status amboy.JobStatusInfo
+	Body   []byte
+}
+
+// MakeJobInterchange encodes j's exported state in the given format and
+// wraps it with the type information Resolve needs to recreate a job of
+// the same concrete type.
+func MakeJobInterchange(j amboy.Job, f amboy.Format) (*JobInterchange, error) {
+	body, err := f.Marshal(j)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding job body")
+	}
+
+	return &JobInterchange{
+		Name:   j.Type().Name,
+		Type:   j.Type(),
+		Status: j.Status(),
+		Body:   body,
+	}, nil
+}
+
+// Resolve looks up the factory registered for the interchange's job type,
+// decodes Body into a new instance of it, and restores its status.
+func (i *JobInterchange) Resolve(f amboy.Format) (amboy.Job, error) {
+	factory, ok := getJobType(i.Name)
+	if !ok {
+		return nil, errors.Errorf("no job type registered for '%s'", i.Name)
+	}
+
+	j := factory()
+	if err := f.Unmarshal(i.Body, j); err != nil {
+		return nil, errors.Wrap(err, "decoding job body")
+	}
+	j.SetStatus(i.Status)
+
+	return j, nil
+}
+
+// Marshal encodes j as a self-describing payload containing both its
+// registered type name and a Format-encoded snapshot of its state, so
+// Unmarshal can later recreate a job of the correct concrete type.
+func Marshal(j amboy.Job, f amboy.Format) ([]byte, error) {
+	interchange, err := MakeJobInterchange(j, f)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(interchange)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding job interchange")
+	}
+
+	return payload, nil
+}
+
+// Unmarshal decodes a payload produced by Marshal back into a job of its
+// original concrete type.
+func Unmarshal(payload []byte, f amboy.Format) (amboy.Job, error) {
+	interchange := &JobInterchange{}
+	if err := json.Unmarshal(payload, interchange); err != nil {
+		return nil, errors.Wrap(err, "decoding job interchange")
+	}
+
+	return interchange.Resolve(f)
+}
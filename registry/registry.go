@@ -0,0 +1,35 @@
+// Package registry provides a way to declare job types so that Queue
+// implementations can persist jobs generically and recreate a job of the
+// correct concrete type later, without the caller needing to know that
+// type ahead of time.
+package registry
+
+import (
+	"sync"
+
+	"github.com/mongodb/amboy"
+)
+
+var (
+	typesMutex sync.RWMutex
+	jobTypes   = map[string]func() amboy.Job{}
+)
+
+// AddJobType registers a factory for the named job type so that
+// JobInterchange.Resolve can recreate jobs of that type. Job
+// implementations typically call this from an init function alongside
+// their type definition.
+func AddJobType(name string, factory func() amboy.Job) {
+	typesMutex.Lock()
+	defer typesMutex.Unlock()
+
+	jobTypes[name] = factory
+}
+
+func getJobType(name string) (func() amboy.Job, bool) {
+	typesMutex.RLock()
+	defer typesMutex.RUnlock()
+
+	factory, ok := jobTypes[name]
+	return factory, ok
+}
@@ -0,0 +1,46 @@
+package amboy
+
+import "context"
+
+// JobRetryInfo describes a job's retry state: whether it opts into
+// retries at all, whether its most recent attempt failed in a way that
+// warrants one, and how many attempts have been made so far.
+type JobRetryInfo struct {
+	Retryable      bool
+	NeedsRetry     bool
+	CurrentAttempt int
+	MaxAttempts    int
+}
+
+// ShouldRetry reports whether the job should be handed to a
+// RetryHandler rather than treated as finished.
+func (i JobRetryInfo) ShouldRetry() bool {
+	return i.Retryable && i.NeedsRetry && i.CurrentAttempt < i.MaxAttempts
+}
+
+// RetryableJob is implemented by jobs that can be safely re-run after a
+// failed attempt. Queue implementations that support retries check for
+// this interface in Complete and, if RetryInfo().ShouldRetry() is true,
+// hand the job to their RetryHandler instead of treating it as finished.
+type RetryableJob interface {
+	Job
+	RetryInfo() JobRetryInfo
+	UpdateRetryInfo(JobRetryInfo)
+}
+
+// RetryHandler manages re-enqueuing RetryableJobs on behalf of a queue.
+type RetryHandler interface {
+	SetQueue(Queue) error
+	Put(ctx context.Context, j RetryableJob) error
+	Start(ctx context.Context) error
+	Close(ctx context.Context)
+}
+
+// RetryableQueue is implemented by queues that can hand retryable jobs off
+// to a RetryHandler in Complete. SetRetryHandler is the only supported way
+// to configure it: callers should never have to reach into a queue's
+// unexported fields to exercise retry behavior.
+type RetryableQueue interface {
+	Queue
+	SetRetryHandler(RetryHandler) error
+}
@@ -0,0 +1,21 @@
+package amboy
+
+import "time"
+
+// Schedulable is an optional interface that a Job may implement to defer
+// its own dispatch until a specific point in time. Queue implementations
+// that support deferred dispatch (see queue.NewDelayedLocal) check for
+// this interface in Next and will not return a job whose RunAt has not
+// yet arrived, enabling "run this in 30 days" style handlers on top of
+// the existing queue/runner primitives.
+type Schedulable interface {
+	Job
+
+	// RunAt reports the earliest time the job should be returned from
+	// Queue.Next. A zero time means the job is eligible immediately.
+	RunAt() time.Time
+
+	// SetRunAt sets the earliest time the job should be returned from
+	// Queue.Next. Callers typically compute this as time.Now().Add(delay).
+	SetRunAt(time.Time)
+}